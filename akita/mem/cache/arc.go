@@ -0,0 +1,214 @@
+package cache
+
+// MissObserver is implemented by victim finders that want to see every
+// cache miss, address included, before FindVictim is called for that
+// miss. It's optional - SRRIP, BRRIP, DRRIP, and LRU don't need it - but
+// ARC uses it to adapt its T1 target size p when the missed address is
+// a ghost hit in B1 or B2.
+type MissObserver interface {
+	OnMiss(set *Set, addr uint64)
+}
+
+// ARCVictimFinder implements Megiddo & Modha's Adaptive Replacement
+// Cache (ARC) policy for a single set.
+//
+// ARC splits a set's capacity c between two resident lists: T1 holds
+// blocks seen exactly once recently, T2 holds blocks seen more than
+// once ("frequent"). Two further ghost lists, B1 and B2, remember the
+// tags of blocks recently evicted from T1 and T2 respectively, without
+// holding their data. A target size p for T1 adapts on every miss: a
+// hit in B1 (a once-seen block came back) grows p, favoring recency; a
+// hit in B2 (a frequent block came back) shrinks p, favoring frequency.
+// This lets ARC track the better of LRU-like and LFU-like behavior
+// without any workload-specific tuning.
+type ARCVictimFinder struct {
+	c int // associativity of the set this instance manages; set on first use
+
+	t1, t2 []*Block // resident lists, ordered LRU -> MRU
+	b1, b2 []uint64 // ghost tag lists, ordered LRU -> MRU
+	tagOf  map[*Block]uint64
+
+	p int // adaptive target size for T1, in [0, c]
+}
+
+// NewARCVictimFinder returns a newly constructed ARC evictor. One
+// instance manages exactly one set; the cache should construct one per
+// set, unlike DRRIPVictimFinder, whose one instance serves every set of
+// the cache.
+func NewARCVictimFinder() *ARCVictimFinder {
+	return &ARCVictimFinder{tagOf: make(map[*Block]uint64)}
+}
+
+// ensureCapacity records the set's associativity the first time ARC sees
+// it; ARC assumes it stays constant afterwards.
+func (e *ARCVictimFinder) ensureCapacity(set *Set) {
+	if e.c == 0 {
+		e.c = len(set.LRUQueue())
+	}
+}
+
+// OnMiss adapts the T1/T2 target size p based on whether addr is a ghost
+// hit. Call this once per miss, before FindVictim.
+func (e *ARCVictimFinder) OnMiss(set *Set, addr uint64) {
+	e.ensureCapacity(set)
+	switch {
+	case containsTag(e.b1, addr):
+		delta := 1
+		if len(e.b1) > 0 {
+			delta = maxInt(1, len(e.b2)/len(e.b1))
+		}
+		e.p = minInt(e.c, e.p+delta)
+	case containsTag(e.b2, addr):
+		delta := 1
+		if len(e.b2) > 0 {
+			delta = maxInt(1, len(e.b1)/len(e.b2))
+		}
+		e.p = maxInt(0, e.p-delta)
+	}
+}
+
+// FindVictim returns the ARC-selected victim for a miss on addr.
+//
+// Like every other finder in this package, it first looks for a free
+// (invalid, unlocked) block in the underlying Set - on a cold or
+// partially filled set, T1/T2 haven't been populated yet (that only
+// happens once OnFill is called on the returned block), so without this
+// probe ARC would have nothing to evict and FindVictim would return nil
+// forever. Only once the set has no free block left does ARC fall back
+// to its own ghost-adaptation eviction: if addr is a ghost hit, its tag
+// is consumed from the matching ghost list (it's about to become
+// resident again) and the victim comes from the other resident list;
+// otherwise the victim comes from T1 or T2 depending on whether T1 has
+// reached its target size p.
+func (e *ARCVictimFinder) FindVictim(set *Set, addr uint64) *Block {
+	e.ensureCapacity(set)
+
+	var free *Block
+	set.ForEachFromLRU(func(b *Block) bool {
+		if !b.IsValid && !b.IsLocked {
+			free = b
+			return false
+		}
+		return true
+	})
+	if free != nil {
+		return free
+	}
+
+	switch {
+	case removeTag(&e.b1, addr):
+		return e.evict(false) // ghost hit in B1: evict LRU of T2
+	case removeTag(&e.b2, addr):
+		return e.evict(true) // ghost hit in B2: evict LRU of T1
+	default:
+		return e.evict(len(e.t1) > 0 && len(e.t1) >= e.p)
+	}
+}
+
+// evict removes the LRU block of T1 (fromT1) or T2, moves its tag to the
+// MRU end of the corresponding ghost list, and returns it. It falls back
+// to the other resident list if the requested one is empty.
+func (e *ARCVictimFinder) evict(fromT1 bool) *Block {
+	if fromT1 && len(e.t1) == 0 {
+		fromT1 = false
+	}
+	if !fromT1 && len(e.t2) == 0 {
+		fromT1 = true
+	}
+
+	var victim *Block
+	if fromT1 && len(e.t1) > 0 {
+		victim, e.t1 = e.t1[0], e.t1[1:]
+		e.b1 = append(e.b1, e.tagOf[victim])
+	} else if len(e.t2) > 0 {
+		victim, e.t2 = e.t2[0], e.t2[1:]
+		e.b2 = append(e.b2, e.tagOf[victim])
+	} else {
+		return nil
+	}
+
+	delete(e.tagOf, victim)
+	e.capGhosts()
+	return victim
+}
+
+// capGhosts trims ghost lists from their LRU end to keep
+// |T1|+|B1| <= c and |T1|+|T2|+|B1|+|B2| <= 2c.
+func (e *ARCVictimFinder) capGhosts() {
+	for len(e.t1)+len(e.b1) > e.c && len(e.b1) > 0 {
+		e.b1 = e.b1[1:]
+	}
+	for len(e.t1)+len(e.t2)+len(e.b1)+len(e.b2) > 2*e.c {
+		if len(e.b1) >= len(e.b2) && len(e.b1) > 0 {
+			e.b1 = e.b1[1:]
+		} else if len(e.b2) > 0 {
+			e.b2 = e.b2[1:]
+		} else {
+			break
+		}
+	}
+}
+
+// OnFill should be called by the cache when b is filled with addr. New
+// blocks always enter at the MRU end of T1.
+func (e *ARCVictimFinder) OnFill(b *Block, addr uint64) {
+	e.t1 = append(e.t1, b)
+	e.tagOf[b] = addr
+}
+
+// OnHit should be called by the cache when b is hit. wasInT1 tells ARC
+// which resident list b was in; either way, a hit promotes b to the MRU
+// end of T2 ("frequent").
+func (e *ARCVictimFinder) OnHit(b *Block, wasInT1 bool) {
+	if wasInT1 {
+		removeBlock(&e.t1, b)
+	} else {
+		removeBlock(&e.t2, b)
+	}
+	e.t2 = append(e.t2, b)
+}
+
+func containsTag(tags []uint64, tag uint64) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTag deletes the first occurrence of tag from *tags, reporting
+// whether it was found.
+func removeTag(tags *[]uint64, tag uint64) bool {
+	for i, t := range *tags {
+		if t == tag {
+			*tags = append((*tags)[:i], (*tags)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// removeBlock deletes the first occurrence of b from *blocks.
+func removeBlock(blocks *[]*Block, b *Block) {
+	for i, v := range *blocks {
+		if v == b {
+			*blocks = append((*blocks)[:i], (*blocks)[i+1:]...)
+			return
+		}
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,201 @@
+package cache
+
+import "testing"
+
+// scanThenReuseTrace builds a trace that touches a small working set
+// every round, immediately before a scan burst twice the size of the
+// cache sweeps through. The working set is touched first, not last: a
+// hit promotes its blocks into ARC's frequent list (T2), which ARC will
+// not evict in favor of a one-time scan address as long as there's
+// anything left in T1 to evict instead - and since the scan burst is
+// unbounded and never repeats, T1 never runs dry. A pure-recency policy
+// like LRU has no such protection: once the burst (2x capacity) has
+// streamed past, the working set is older than everything resident and
+// gets swept out with it. SRRIP fares no better here - ageAll bumps
+// every resident block's RRPV on every burst address that doesn't find
+// an immediate rrpvMax victim, so the working set's protected RRPV==0
+// ages back up to rrpvMax well before its next touch, same as LRU.
+func scanThenReuseTrace(capacity int) []uint64 {
+	trace := make([]uint64, 0)
+
+	workingSet := capacity / 2
+	scanBurst := capacity * 2
+	nextScanAddr := uint64(workingSet)
+	for i := 0; i < workingSet; i++ {
+		trace = append(trace, uint64(i))
+	}
+	for round := 0; round < 20; round++ {
+		for i := 0; i < workingSet; i++ {
+			trace = append(trace, uint64(i))
+		}
+		for i := 0; i < scanBurst; i++ {
+			trace = append(trace, nextScanAddr)
+			nextScanAddr++
+		}
+	}
+
+	return trace
+}
+
+// newTestSet builds a Set with capacity cold (invalid) blocks already
+// linked into its intrusive LRU list, as a real cache would have after
+// allocating a set's ways.
+func newTestSet(capacity int) *Set {
+	set := &Set{}
+	for i := 0; i < capacity; i++ {
+		set.InsertMRU(&Block{})
+	}
+	return set
+}
+
+func hitRate(hits, misses int) float64 {
+	return float64(hits) / float64(hits+misses)
+}
+
+func runARCTrace(trace []uint64, capacity int) float64 {
+	set := newTestSet(capacity)
+	finder := NewARCVictimFinder()
+
+	resident := make(map[uint64]*Block)
+	addrOf := make(map[*Block]uint64)
+	inT1 := make(map[*Block]bool)
+
+	hits, misses := 0, 0
+	for _, addr := range trace {
+		if b, ok := resident[addr]; ok {
+			hits++
+			finder.OnHit(b, inT1[b])
+			inT1[b] = false
+			continue
+		}
+
+		misses++
+		finder.OnMiss(set, addr)
+		victim := finder.FindVictim(set, addr)
+		if oldAddr, ok := addrOf[victim]; ok {
+			delete(resident, oldAddr)
+		}
+		victim.IsValid = true
+		finder.OnFill(victim, addr)
+		addrOf[victim] = addr
+		resident[addr] = victim
+		inT1[victim] = true
+	}
+	return hitRate(hits, misses)
+}
+
+func runLRUTrace(trace []uint64, capacity int) float64 {
+	set := newTestSet(capacity)
+	finder := NewLRUVictimFinder()
+
+	resident := make(map[uint64]*Block)
+	addrOf := make(map[*Block]uint64)
+
+	hits, misses := 0, 0
+	for _, addr := range trace {
+		if b, ok := resident[addr]; ok {
+			hits++
+			set.TouchMRU(b)
+			continue
+		}
+
+		misses++
+		victim := finder.FindVictim(set)
+		if oldAddr, ok := addrOf[victim]; ok {
+			delete(resident, oldAddr)
+		}
+		victim.IsValid = true
+		set.TouchMRU(victim)
+		addrOf[victim] = addr
+		resident[addr] = victim
+	}
+	return hitRate(hits, misses)
+}
+
+func runSRRIPTrace(trace []uint64, capacity int) float64 {
+	set := newTestSet(capacity)
+	finder := NewSRRIPVictimFinder()
+
+	resident := make(map[uint64]*Block)
+	addrOf := make(map[*Block]uint64)
+
+	hits, misses := 0, 0
+	for _, addr := range trace {
+		if b, ok := resident[addr]; ok {
+			hits++
+			finder.OnHit(b)
+			continue
+		}
+
+		misses++
+		victim := finder.FindVictim(set)
+		if oldAddr, ok := addrOf[victim]; ok {
+			delete(resident, oldAddr)
+		}
+		victim.IsValid = true
+		finder.OnFill(victim)
+		addrOf[victim] = addr
+		resident[addr] = victim
+	}
+	return hitRate(hits, misses)
+}
+
+const benchCacheCapacity = 16
+
+// TestARCScanThenReuseBeatsLRUAndSRRIP pins down the actual point of
+// scanThenReuseTrace: on it, ARC's frequent list (T2) must keep the
+// working set alive across a scan burst that defeats both a pure-recency
+// policy (LRU) and RRPV aging (SRRIP). If this ever regresses back to a
+// three-way tie, the trace has stopped exercising ARC's advantage and
+// needs fixing again, not this assertion relaxing.
+func TestARCScanThenReuseBeatsLRUAndSRRIP(t *testing.T) {
+	trace := scanThenReuseTrace(benchCacheCapacity)
+
+	arcRate := runARCTrace(trace, benchCacheCapacity)
+	lruRate := runLRUTrace(trace, benchCacheCapacity)
+	srripRate := runSRRIPTrace(trace, benchCacheCapacity)
+
+	const minRate = 0.15
+	if arcRate < minRate {
+		t.Fatalf("ARC hit rate = %.3f, want at least %.3f on a trace built to keep its working set resident", arcRate, minRate)
+	}
+
+	const maxRivalRate = 0.05
+	if lruRate > maxRivalRate {
+		t.Fatalf("LRU hit rate = %.3f, want at most %.3f - it should never recognize the working set as anything but recently evicted", lruRate, maxRivalRate)
+	}
+	if srripRate > maxRivalRate {
+		t.Fatalf("SRRIP hit rate = %.3f, want at most %.3f - RRPV aging should age the working set out same as LRU", srripRate, maxRivalRate)
+	}
+
+	if arcRate < lruRate*3 || arcRate < srripRate*3 {
+		t.Fatalf("ARC (%.3f) does not clearly beat LRU (%.3f) or SRRIP (%.3f) on this trace", arcRate, lruRate, srripRate)
+	}
+}
+
+func BenchmarkARCScanThenReuse(b *testing.B) {
+	trace := scanThenReuseTrace(benchCacheCapacity)
+	var rate float64
+	for i := 0; i < b.N; i++ {
+		rate = runARCTrace(trace, benchCacheCapacity)
+	}
+	b.ReportMetric(rate*100, "hit-%")
+}
+
+func BenchmarkLRUScanThenReuse(b *testing.B) {
+	trace := scanThenReuseTrace(benchCacheCapacity)
+	var rate float64
+	for i := 0; i < b.N; i++ {
+		rate = runLRUTrace(trace, benchCacheCapacity)
+	}
+	b.ReportMetric(rate*100, "hit-%")
+}
+
+func BenchmarkSRRIPScanThenReuse(b *testing.B) {
+	trace := scanThenReuseTrace(benchCacheCapacity)
+	var rate float64
+	for i := 0; i < b.N; i++ {
+		rate = runSRRIPTrace(trace, benchCacheCapacity)
+	}
+	b.ReportMetric(rate*100, "hit-%")
+}
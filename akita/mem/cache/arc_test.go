@@ -0,0 +1,92 @@
+package cache
+
+import "testing"
+
+// TestARCFindVictimFillsColdSetFromFreeBlocks is the regression test for
+// the bug a reviewer caught: on a cold set (T1/T2 still empty), FindVictim
+// must hand out the Set's existing free blocks instead of returning nil
+// forever.
+func TestARCFindVictimFillsColdSetFromFreeBlocks(t *testing.T) {
+	set := newTestSet(4)
+	finder := NewARCVictimFinder()
+
+	for i := 0; i < 4; i++ {
+		addr := uint64(i)
+		finder.OnMiss(set, addr)
+		victim := finder.FindVictim(set, addr)
+		if victim == nil {
+			t.Fatalf("fill %d: FindVictim returned nil on a cold set", i)
+		}
+		victim.IsValid = true
+		finder.OnFill(victim, addr)
+	}
+}
+
+// TestARCEvictsLRUOfT1OnCapacityMiss exercises the steady-state path once
+// the set is warm and a miss forces a real eviction: with p==0 (favoring
+// T2), a miss not present in either ghost list should evict out of T1.
+func TestARCEvictsLRUOfT1OnCapacityMiss(t *testing.T) {
+	set := newTestSet(2)
+	finder := NewARCVictimFinder()
+
+	finder.OnMiss(set, 0)
+	first := finder.FindVictim(set, 0)
+	first.IsValid = true
+	finder.OnFill(first, 0)
+
+	finder.OnMiss(set, 1)
+	second := finder.FindVictim(set, 1)
+	second.IsValid = true
+	finder.OnFill(second, 1)
+
+	// Set is now full and p==0, so a miss on a brand new address must
+	// evict from T1, LRU-first: that's "first" (address 0).
+	finder.OnMiss(set, 2)
+	victim := finder.FindVictim(set, 2)
+	if victim != first {
+		t.Fatalf("FindVictim = %v, want the LRU-most T1 block (first)", victim)
+	}
+}
+
+// TestARCGhostHitPromotesFromOtherList checks the ghost-adaptation path: a
+// miss that hits B1 (a recently evicted T1 tag) must evict from T2, not
+// T1, and must grow p.
+func TestARCGhostHitPromotesFromOtherList(t *testing.T) {
+	set := newTestSet(2)
+	finder := NewARCVictimFinder()
+
+	finder.OnMiss(set, 0)
+	a := finder.FindVictim(set, 0)
+	a.IsValid = true
+	finder.OnFill(a, 0)
+
+	finder.OnMiss(set, 1)
+	b := finder.FindVictim(set, 1)
+	b.IsValid = true
+	finder.OnFill(b, 1)
+
+	// A hit on b promotes it into T2, leaving T1={a}, T2={b}.
+	finder.OnHit(b, true)
+
+	// Evict a (address 0) into B1 via a miss on a fresh address.
+	finder.OnMiss(set, 2)
+	evicted := finder.FindVictim(set, 2)
+	if evicted != a {
+		t.Fatalf("expected to evict a (address 0), got %v", evicted)
+	}
+	evicted.IsValid = true
+	finder.OnFill(evicted, 2)
+
+	pBefore := finder.p
+
+	// Address 0 is now a ghost in B1; missing on it again must grow p and
+	// evict from T2 (b), not from T1.
+	finder.OnMiss(set, 0)
+	if finder.p <= pBefore {
+		t.Fatalf("B1 ghost hit: p = %d, want it to grow above %d", finder.p, pBefore)
+	}
+	victim := finder.FindVictim(set, 0)
+	if victim != b {
+		t.Fatalf("B1 ghost hit: evicted %v, want T2's block (b)", victim)
+	}
+}
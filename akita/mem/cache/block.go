@@ -0,0 +1,14 @@
+package cache
+
+// Block represents the state of a single cache line (a "way" within a
+// Set) that victim finders reason about.
+type Block struct {
+	IsValid  bool
+	IsLocked bool
+
+	// prev and next link the block into its Set's intrusive LRU list
+	// (see Set). They are maintained exclusively by Set's
+	// InsertMRU/TouchMRU/Remove; victim finders should not set them
+	// directly.
+	prev, next *Block
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"math/rand"
+	"time"
+)
+
+// bimodalThrottle rolls the bimodal insertion decision BRRIP-style
+// policies need: with probability btp, insert like SRRIP would
+// (insertRRPV); otherwise insert "distant" (rrpvMax). It's split out of
+// BRRIPVictimFinder so DRRIP can reuse the same roll against its own
+// shared rrpvTracker instead of carrying around a whole unused BRRIP
+// finder just for this.
+type bimodalThrottle struct {
+	btp uint8 // bimodal throttle percent, 0..100
+	rng *rand.Rand
+}
+
+// newBimodalThrottle returns a throttle seeded from the current time;
+// call SetSource to replace it with a deterministic source for tests.
+func newBimodalThrottle(btpPercent uint8) *bimodalThrottle {
+	return &bimodalThrottle{
+		btp: btpPercent,
+		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// SetSource replaces the throttle's random source. It exists so unit
+// tests can pass a deterministic, seedable rand.Source (e.g.
+// rand.NewSource(1)) and replay the exact sequence of bimodal throttle
+// decisions.
+func (t *bimodalThrottle) SetSource(src rand.Source) {
+	t.rng = rand.New(src)
+}
+
+// roll returns insertRRPV with probability btp, rrpvMax otherwise.
+func (t *bimodalThrottle) roll(insertRRPV, rrpvMax uint) uint {
+	if t.rng.Intn(100) < int(t.btp) {
+		return insertRRPV
+	}
+	return rrpvMax
+}
+
+// BRRIPVictimFinder implements Bimodal RRIP.
+//
+// BRRIP differs from SRRIP only in its insertion policy: most fills are
+// inserted at rrpvMax ("distant", likely to be evicted soon), and only a
+// small, configurable fraction (btp, the bimodal throttle percent) are
+// inserted at insertRRPV like SRRIP would. This bimodal mix is what makes
+// BRRIP resistant to scans and thrashing working sets that are larger
+// than the cache: most of a scan's insertions are immediately eligible
+// for eviction instead of displacing useful lines for a full insertRRPV
+// lifetime.
+//
+// The scan/age/hit bookkeeping is shared with SRRIPVictimFinder via
+// rrpvTracker; BRRIP only supplies its own insertion policy.
+type BRRIPVictimFinder struct {
+	*rrpvTracker
+	*bimodalThrottle
+}
+
+// NewBRRIPVictimFinderBits returns a newly constructed BRRIP evictor
+// whose RRPV is numBits wide. btpPercent is the percentage (0..100) of
+// fills inserted at insertRRPV instead of rrpvMax; gem5's BRRIPRP
+// typically uses a small value such as 3.
+func NewBRRIPVictimFinderBits(numBits int, btpPercent uint8) *BRRIPVictimFinder {
+	return NewBRRIPVictimFinderHitPriority(numBits, btpPercent, false)
+}
+
+// NewBRRIPVictimFinderHitPriority returns a newly constructed BRRIP
+// evictor with hit_priority eviction enabled or disabled; see
+// NewSRRIPVictimFinderHitPriority for what it does.
+func NewBRRIPVictimFinderHitPriority(numBits int, btpPercent uint8, hitPriority bool) *BRRIPVictimFinder {
+	return &BRRIPVictimFinder{
+		rrpvTracker:     newRRPVTracker(numBits, hitPriority),
+		bimodalThrottle: newBimodalThrottle(btpPercent),
+	}
+}
+
+// NewBRRIPVictimFinder returns a newly constructed BRRIP evictor using
+// the conventional 2-bit RRPV.
+func NewBRRIPVictimFinder(btpPercent uint8) *BRRIPVictimFinder {
+	return NewBRRIPVictimFinderBits(2, btpPercent)
+}
+
+// OnHit should be called by the cache when a block is hit.
+func (e *BRRIPVictimFinder) OnHit(b *Block) {
+	e.onHit(b)
+}
+
+// OnFill should be called by the cache when a block is filled/inserted.
+// With probability btp the block is inserted at insertRRPV; otherwise it
+// is inserted at rrpvMax.
+func (e *BRRIPVictimFinder) OnFill(b *Block) {
+	e.onFill(b, e.insertValue())
+}
+
+// insertValue rolls the bimodal throttle and returns the RRPV a newly
+// filled block should be inserted at.
+func (e *BRRIPVictimFinder) insertValue() uint {
+	return e.roll(e.insertRRPV, e.rrpvMax)
+}
+
+// FindVictim returns the BRRIP-selected victim in the set.
+func (e *BRRIPVictimFinder) FindVictim(set *Set) *Block {
+	return e.findVictim(set)
+}
+
+// Reset clears all bookkeeping, forgetting every block's RRPV and hit
+// history.
+func (e *BRRIPVictimFinder) Reset() {
+	e.reset()
+}
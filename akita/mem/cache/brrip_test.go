@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBRRIPInsertValueThrottleBounds(t *testing.T) {
+	zero := NewBRRIPVictimFinder(0)
+	zero.SetSource(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if v := zero.insertValue(); v != zero.rrpvMax {
+			t.Fatalf("btp=0: insertValue() = %d, want rrpvMax (%d)", v, zero.rrpvMax)
+		}
+	}
+
+	full := NewBRRIPVictimFinder(100)
+	full.SetSource(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if v := full.insertValue(); v != full.insertRRPV {
+			t.Fatalf("btp=100: insertValue() = %d, want insertRRPV (%d)", v, full.insertRRPV)
+		}
+	}
+}
+
+// TestBRRIPSeedReplay exercises the whole point of SetSource: two
+// finders seeded identically must roll the same sequence of bimodal
+// throttle decisions, so a test can replay a specific trace
+// deterministically.
+func TestBRRIPSeedReplay(t *testing.T) {
+	const seed = 42
+	a := NewBRRIPVictimFinder(50)
+	a.SetSource(rand.NewSource(seed))
+	b := NewBRRIPVictimFinder(50)
+	b.SetSource(rand.NewSource(seed))
+
+	for i := 0; i < 50; i++ {
+		va, vb := a.insertValue(), b.insertValue()
+		if va != vb {
+			t.Fatalf("roll %d: replay diverged: %d != %d", i, va, vb)
+		}
+	}
+}
+
+func TestBRRIPOnFillAndOnHit(t *testing.T) {
+	e := NewBRRIPVictimFinder(0) // btp=0: every fill inserts at rrpvMax
+	b := &Block{IsValid: true}
+
+	e.OnFill(b)
+	if got := e.counter(b).Val(); got != e.rrpvMax {
+		t.Fatalf("OnFill: RRPV = %d, want rrpvMax (%d)", got, e.rrpvMax)
+	}
+
+	e.OnHit(b)
+	if got := e.counter(b).Val(); got != hitRRPV {
+		t.Fatalf("OnHit: RRPV = %d, want hitRRPV (%d)", got, hitRRPV)
+	}
+}
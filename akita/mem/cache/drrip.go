@@ -0,0 +1,141 @@
+package cache
+
+// DRRIPVictimFinder implements Dynamic RRIP, which uses set dueling to
+// pick, per access stream, whichever of SRRIP or BRRIP is winning on this
+// workload.
+//
+// A small number of sets are permanently dedicated as "leader sets": some
+// always use SRRIP insertion, the rest always use BRRIP insertion. Every
+// miss in a leader set nudges a single saturating Policy-Selector counter
+// (PSEL) towards whichever policy is losing fewer misses: SRRIP leader
+// misses increment PSEL, BRRIP leader misses decrement it. The remaining
+// "follower" sets - almost the whole cache - pick SRRIP or BRRIP
+// insertion for each fill based on which half of PSEL's range it's
+// currently in. This lets the cache track whichever policy suits the
+// current workload without profiling it offline.
+//
+// Victim selection and hit handling are identical between SRRIP and
+// BRRIP (both just track RRPV and evict/age on rrpvMax), so DRRIP owns
+// a single rrpvTracker for that shared bookkeeping and only decides the
+// insertion RRPV itself: insertRRPV for SRRIP-style fills, or a
+// bimodalThrottle roll against that same tracker for BRRIP-style fills.
+type DRRIPVictimFinder struct {
+	tracker *rrpvTracker
+	bimodal *bimodalThrottle
+
+	numSets      int
+	srripLeaders map[int]bool
+	brripLeaders map[int]bool
+	psel         *SatCounter // 10-bit PSEL, 0..1023
+}
+
+const pselBits = 10
+
+// NewDRRIPVictimFinderBits returns a newly constructed DRRIP evictor
+// whose RRPV is numBits wide. numSets is the total number of sets in the
+// cache; numLeaderSetsPerPolicy is how many of those sets are dedicated
+// to each of SRRIP and BRRIP (e.g. 32 and 32, leaving the rest as
+// followers) - if it's more than numSets/2, it's silently clamped down
+// to numSets/2 so the two leader groups never overlap; btp is BRRIP's
+// bimodal throttle percent.
+func NewDRRIPVictimFinderBits(numSets, numLeaderSetsPerPolicy int, btp uint8, numBits int) *DRRIPVictimFinder {
+	srripLeaders, brripLeaders := pickLeaderSets(numSets, numLeaderSetsPerPolicy)
+	psel := NewSatCounter(pselBits, 0)
+	psel.Set(psel.Max()/2 + 1)
+	return &DRRIPVictimFinder{
+		tracker:      newRRPVTracker(numBits, false),
+		bimodal:      newBimodalThrottle(btp),
+		numSets:      numSets,
+		srripLeaders: srripLeaders,
+		brripLeaders: brripLeaders,
+		psel:         psel,
+	}
+}
+
+// NewDRRIPVictimFinder returns a newly constructed DRRIP evictor using
+// the conventional 2-bit RRPV.
+func NewDRRIPVictimFinder(numSets, numLeaderSetsPerPolicy int, btp uint8) *DRRIPVictimFinder {
+	return NewDRRIPVictimFinderBits(numSets, numLeaderSetsPerPolicy, btp, 2)
+}
+
+// pickLeaderSets assigns numLeaderSetsPerPolicy sets to SRRIP and another
+// numLeaderSetsPerPolicy to BRRIP, spreading both groups evenly across
+// the index space by interleaving them at a fixed stride. The two
+// groups are always disjoint: numLeaderSetsPerPolicy is clamped to
+// numSets/2 first, so there's always room for both groups without the
+// modulo wrap that a too-large request would otherwise cause, and the
+// stride is then derived from the clamped count instead of the
+// caller's.
+func pickLeaderSets(numSets, numLeaderSetsPerPolicy int) (srripLeaders, brripLeaders map[int]bool) {
+	n := numLeaderSetsPerPolicy
+	if n > numSets/2 {
+		n = numSets / 2
+	}
+	srripLeaders = make(map[int]bool, n)
+	brripLeaders = make(map[int]bool, n)
+	if numSets == 0 || n == 0 {
+		return
+	}
+	stride := numSets / (2 * n)
+	if stride == 0 {
+		stride = 1
+	}
+	for i := 0; i < n; i++ {
+		srripLeaders[(i*2*stride)%numSets] = true
+		brripLeaders[(i*2*stride+stride)%numSets] = true
+	}
+	return
+}
+
+// OnHit should be called by the cache when a block is hit.
+func (e *DRRIPVictimFinder) OnHit(b *Block) {
+	e.tracker.onHit(b)
+}
+
+// OnMiss should be called by the cache on every miss in setIndex, before
+// the replacement fill, so PSEL can track which policy is winning.
+func (e *DRRIPVictimFinder) OnMiss(setIndex int) {
+	switch {
+	case e.srripLeaders[setIndex]:
+		e.psel.Inc()
+	case e.brripLeaders[setIndex]:
+		e.psel.Dec()
+	}
+}
+
+// OnFill should be called by the cache when a block is filled/inserted
+// into setIndex. The insertion RRPV is chosen by the set's dueling role:
+// leader sets always use their assigned policy, follower sets use
+// whichever policy PSEL currently favors.
+func (e *DRRIPVictimFinder) OnFill(setIndex int, b *Block) {
+	var useBRRIP bool
+	switch {
+	case e.srripLeaders[setIndex]:
+		useBRRIP = false
+	case e.brripLeaders[setIndex]:
+		useBRRIP = true
+	default:
+		useBRRIP = e.psel.Val() > e.psel.Max()/2
+	}
+
+	if useBRRIP {
+		e.tracker.onFill(b, e.bimodal.roll(e.tracker.insertRRPV, e.tracker.rrpvMax))
+	} else {
+		e.tracker.onFill(b, e.tracker.insertRRPV)
+	}
+}
+
+// FindVictim returns the DRRIP-selected victim in the set. Victim
+// selection does not depend on a set's dueling role, so this simply
+// delegates to the shared RRPV scan/age logic.
+func (e *DRRIPVictimFinder) FindVictim(set *Set) *Block {
+	return e.tracker.findVictim(set)
+}
+
+// FindVictimInSet is the set-aware form of FindVictim. DRRIP's victim
+// selection is identical across sets, so setIndex is unused today, but
+// the method exists so the cache can always call the set-aware API
+// uniformly across policies.
+func (e *DRRIPVictimFinder) FindVictimInSet(setIndex int, set *Set) *Block {
+	return e.tracker.findVictim(set)
+}
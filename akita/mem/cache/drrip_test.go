@@ -0,0 +1,82 @@
+package cache
+
+import "testing"
+
+func TestDRRIPLeaderSetsDisjoint(t *testing.T) {
+	srrip, brrip := pickLeaderSets(128, 16)
+	if len(srrip) != 16 || len(brrip) != 16 {
+		t.Fatalf("got %d SRRIP leaders, %d BRRIP leaders, want 16 each", len(srrip), len(brrip))
+	}
+	for s := range srrip {
+		if brrip[s] {
+			t.Fatalf("set %d assigned to both SRRIP and BRRIP leaders", s)
+		}
+	}
+}
+
+// TestDRRIPLeaderSetsDisjointWhenOverrequested exercises the case where
+// numLeaderSetsPerPolicy doesn't leave room for two disjoint groups: the
+// stride collapsing to 1 must not let the modulo-wrapped index
+// sequences collide between the two leader groups.
+func TestDRRIPLeaderSetsDisjointWhenOverrequested(t *testing.T) {
+	srrip, brrip := pickLeaderSets(11, 6)
+	if len(srrip) != len(brrip) {
+		t.Fatalf("got %d SRRIP leaders, %d BRRIP leaders, want equal-sized groups", len(srrip), len(brrip))
+	}
+	for s := range srrip {
+		if brrip[s] {
+			t.Fatalf("set %d assigned to both SRRIP and BRRIP leaders", s)
+		}
+	}
+}
+
+func TestDRRIPPSELTracksLeaderMisses(t *testing.T) {
+	e := NewDRRIPVictimFinder(128, 16, 3)
+	var srripLeader, brripLeader int
+	for s := range e.srripLeaders {
+		srripLeader = s
+		break
+	}
+	for s := range e.brripLeaders {
+		brripLeader = s
+		break
+	}
+
+	start := e.psel.Val()
+	e.OnMiss(srripLeader)
+	if got := e.psel.Val(); got != start+1 {
+		t.Fatalf("SRRIP leader miss: PSEL = %d, want %d", got, start+1)
+	}
+	e.OnMiss(brripLeader)
+	if got := e.psel.Val(); got != start {
+		t.Fatalf("BRRIP leader miss: PSEL = %d, want %d", got, start)
+	}
+}
+
+func TestDRRIPFollowerFollowsPSEL(t *testing.T) {
+	e := NewDRRIPVictimFinder(128, 16, 0) // btp=0: BRRIP fills always land at rrpvMax
+	follower := -1
+	for s := 0; s < e.numSets; s++ {
+		if !e.srripLeaders[s] && !e.brripLeaders[s] {
+			follower = s
+			break
+		}
+	}
+	if follower == -1 {
+		t.Fatal("no follower set found")
+	}
+
+	e.psel.Set(0) // favors SRRIP
+	b1 := &Block{IsValid: true}
+	e.OnFill(follower, b1)
+	if got := e.tracker.counter(b1).Val(); got != e.tracker.insertRRPV {
+		t.Fatalf("PSEL low: insert RRPV = %d, want SRRIP's insertRRPV (%d)", got, e.tracker.insertRRPV)
+	}
+
+	e.psel.Set(e.psel.Max()) // favors BRRIP
+	b2 := &Block{IsValid: true}
+	e.OnFill(follower, b2)
+	if got := e.tracker.counter(b2).Val(); got != e.tracker.rrpvMax {
+		t.Fatalf("PSEL high: insert RRPV = %d, want rrpvMax (%d)", got, e.tracker.rrpvMax)
+	}
+}
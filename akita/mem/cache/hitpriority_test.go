@@ -0,0 +1,74 @@
+package cache
+
+import "testing"
+
+// TestSRRIPHitPriorityPrefersNeverHit builds a set where two blocks tie at
+// rrpvMax: one has been hit before, the other never has. With hit_priority
+// enabled, the never-hit block must be evicted first even though both are
+// equally "distant" by RRPV alone.
+func TestSRRIPHitPriorityPrefersNeverHit(t *testing.T) {
+	finder := NewSRRIPVictimFinderHitPriority(2, true)
+	set := newTestSet(2)
+
+	neverHit := finder.FindVictim(set)
+	neverHit.IsValid = true
+	finder.OnFill(neverHit)
+
+	hitOnce := finder.FindVictim(set)
+	hitOnce.IsValid = true
+	finder.OnFill(hitOnce)
+	finder.OnHit(hitOnce) // protects hitOnce, then lets it age back up
+
+	finder.counter(neverHit).Set(finder.rrpvMax)
+	finder.counter(hitOnce).Set(finder.rrpvMax)
+
+	victim := finder.FindVictim(set)
+	if victim != neverHit {
+		t.Fatalf("hit_priority: evicted %v, want the never-hit block", victim)
+	}
+}
+
+// TestSRRIPWithoutHitPriorityIgnoresHitHistory confirms the default (no
+// hit_priority) behavior is unaffected: among blocks tied at rrpvMax, the
+// first one found walking from the LRU end wins regardless of hit history.
+func TestSRRIPWithoutHitPriorityIgnoresHitHistory(t *testing.T) {
+	finder := NewSRRIPVictimFinder() // hit_priority off by default
+	set := newTestSet(2)
+
+	first := finder.FindVictim(set)
+	first.IsValid = true
+	finder.OnFill(first)
+	finder.OnHit(first)
+
+	second := finder.FindVictim(set)
+	second.IsValid = true
+	finder.OnFill(second)
+
+	finder.counter(first).Set(finder.rrpvMax)
+	finder.counter(second).Set(finder.rrpvMax)
+
+	victim := finder.FindVictim(set)
+	if victim != first {
+		t.Fatalf("no hit_priority: evicted %v, want the LRU-order match (%v) regardless of hit history", victim, first)
+	}
+}
+
+func TestBRRIPHitPriorityPrefersNeverHit(t *testing.T) {
+	finder := NewBRRIPVictimFinderHitPriority(2, 0, true) // btp=0: always insert at rrpvMax
+	set := newTestSet(2)
+
+	neverHit := finder.FindVictim(set)
+	neverHit.IsValid = true
+	finder.OnFill(neverHit)
+
+	hitOnce := finder.FindVictim(set)
+	hitOnce.IsValid = true
+	finder.OnFill(hitOnce)
+	finder.OnHit(hitOnce)
+	finder.counter(hitOnce).Set(finder.rrpvMax)
+
+	victim := finder.FindVictim(set)
+	if victim != neverHit {
+		t.Fatalf("BRRIP hit_priority: evicted %v, want the never-hit block", victim)
+	}
+}
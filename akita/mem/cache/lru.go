@@ -0,0 +1,45 @@
+package cache
+
+// LRUVictimFinder evicts the least recently used block in a set.
+//
+// It does no bookkeeping of its own: Set's intrusive list (see Set) is
+// already kept in LRU order by the cache calling TouchMRU/InsertMRU on
+// hits and fills, so finding the victim is just walking from the LRU
+// sentinel and stopping at the first eligible block.
+type LRUVictimFinder struct {
+}
+
+// NewLRUVictimFinder returns a newly constructed lru evictor
+func NewLRUVictimFinder() *LRUVictimFinder {
+	e := new(LRUVictimFinder)
+	return e
+}
+
+// FindVictim returns the least recently used block in a set
+func (e *LRUVictimFinder) FindVictim(set *Set) *Block {
+	// First try evicting an empty block
+	var victim *Block
+	set.ForEachFromLRU(func(block *Block) bool {
+		if !block.IsValid && !block.IsLocked {
+			victim = block
+			return false
+		}
+		return true
+	})
+	if victim != nil {
+		return victim
+	}
+
+	set.ForEachFromLRU(func(block *Block) bool {
+		if !block.IsLocked {
+			victim = block
+			return false
+		}
+		return true
+	})
+	if victim != nil {
+		return victim
+	}
+
+	return set.LRUQueue()[0]
+}
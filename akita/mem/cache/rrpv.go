@@ -0,0 +1,185 @@
+package cache
+
+const hitRRPV = uint(0) // On hit, protect the block
+
+// rrpvTracker holds the RRPV bookkeeping shared by the whole RRIP family
+// (SRRIP, BRRIP, and DRRIP, which composes one directly). SRRIP and
+// BRRIP only disagree on the RRPV a fill inserts at, so that's the one
+// thing left out of here: everything else - the free-block probe, the
+// RRPV-max scan (with or without hit_priority), aging, and hit handling
+// - is identical and lives in one place instead of being copy-pasted per
+// policy.
+type rrpvTracker struct {
+	rrpv map[*Block]*SatCounter
+
+	numBits    int
+	rrpvMax    uint
+	insertRRPV uint
+
+	hitPriority bool
+	hit         map[*Block]bool
+}
+
+// newRRPVTracker returns a tracker with an numBits-wide RRPV. rrpvMax is
+// (1<<numBits)-1 and insertRRPV is one below that (0 for numBits==1,
+// NRU's behavior of marking a filled block recently used, the same as a
+// hit). With hitPriority, FindVictim prefers never-hit
+// blocks over previously-hit ones when several tie at rrpvMax.
+func newRRPVTracker(numBits int, hitPriority bool) *rrpvTracker {
+	rrpvMax := uint(1)<<uint(numBits) - 1
+	insertRRPV := rrpvMax
+	if rrpvMax > 0 {
+		insertRRPV = rrpvMax - 1
+	}
+	return &rrpvTracker{
+		rrpv:        make(map[*Block]*SatCounter),
+		numBits:     numBits,
+		rrpvMax:     rrpvMax,
+		insertRRPV:  insertRRPV,
+		hitPriority: hitPriority,
+		hit:         make(map[*Block]bool),
+	}
+}
+
+// onHit sets b's RRPV to hitRRPV (protect) and records that b has now
+// been hit at least once.
+func (t *rrpvTracker) onHit(b *Block) {
+	t.counter(b).Set(hitRRPV)
+	t.hit[b] = true
+}
+
+// onFill inserts b at the given RRPV and clears its hit history. The
+// insert RRPV itself is policy-specific (SRRIP always uses insertRRPV,
+// BRRIP rolls the bimodal throttle), so callers pass it in.
+func (t *rrpvTracker) onFill(b *Block, insertRRPV uint) {
+	t.counter(b).Set(insertRRPV)
+	t.hit[b] = false
+}
+
+// findVictim returns the tracker-selected victim in the set.
+// Priority:
+//  1. An invalid & unlocked block (free frame) - immediate return.
+//  2. Any block with RRPV==rrpvMax and not locked.
+//  3. Otherwise, age all candidates (RRPV++) and retry until (2) succeeds.
+//
+// If everything is locked, fall back to the first entry in LRU order.
+func (t *rrpvTracker) findVictim(set *Set) *Block {
+	// 1) First try to find a free (invalid) and unlocked block. Walking
+	//    from the LRU sentinel stops at the first match instead of
+	//    scanning (and allocating a snapshot of) the whole set.
+	var free *Block
+	set.ForEachFromLRU(func(b *Block) bool {
+		if !b.IsValid && !b.IsLocked {
+			free = b
+			return false
+		}
+		return true
+	})
+	if free != nil {
+		// Initialize bookkeeping for previously unseen blocks.
+		t.counter(free)
+		return free
+	}
+
+	// 2) Try immediate victim with RRPV==rrpvMax.
+	if v := t.findRRPVMax(set); v != nil {
+		return v
+	}
+
+	// 3) Age until someone reaches RRPV==rrpvMax.
+	//    This will terminate in at most rrpvMax steps.
+	for {
+		t.ageAll(set)
+		if v := t.findRRPVMax(set); v != nil {
+			return v
+		}
+		// In pathological cases where all blocks are locked, break like the LRU reference code.
+		allLocked := true
+		set.ForEachFromLRU(func(b *Block) bool {
+			if !b.IsLocked {
+				allLocked = false
+				return false
+			}
+			return true
+		})
+		if allLocked {
+			break
+		}
+	}
+
+	// Match the LRU fallback behavior if everything ends up locked.
+	// (Caller may still reject a locked block; this mirrors the given reference.)
+	if queue := set.LRUQueue(); len(queue) > 0 {
+		return queue[0]
+	}
+	return nil
+}
+
+// findRRPVMax returns the first eligible block at rrpvMax. With
+// hitPriority, it makes two passes: never-hit blocks first, then
+// previously-hit blocks, so demonstrated reuse is protected a little
+// longer even after both have aged to the same RRPV.
+func (t *rrpvTracker) findRRPVMax(set *Set) *Block {
+	if !t.hitPriority {
+		return t.scanRRPVMax(set, nil)
+	}
+	neverHit := false
+	if v := t.scanRRPVMax(set, &neverHit); v != nil {
+		return v
+	}
+	previouslyHit := true
+	return t.scanRRPVMax(set, &previouslyHit)
+}
+
+// scanRRPVMax walks the set from LRU to MRU looking for an unlocked
+// block at rrpvMax. If wantHit is non-nil, only blocks whose hit status
+// matches *wantHit are considered. This runs on essentially every miss
+// (and up to rrpvMax times per miss from the aging retry loop), so it
+// walks the intrusive list directly and stops at the first match instead
+// of allocating a fresh LRUQueue() snapshot per call.
+func (t *rrpvTracker) scanRRPVMax(set *Set, wantHit *bool) *Block {
+	var found *Block
+	set.ForEachFromLRU(func(b *Block) bool {
+		if b.IsLocked {
+			return true
+		}
+		if t.counter(b).Val() != t.rrpvMax {
+			return true
+		}
+		if wantHit != nil && t.hit[b] != *wantHit {
+			return true
+		}
+		found = b
+		return false
+	})
+	return found
+}
+
+// counter returns the SatCounter tracking b's RRPV, creating one at
+// insertRRPV for previously unseen blocks.
+func (t *rrpvTracker) counter(b *Block) *SatCounter {
+	c, ok := t.rrpv[b]
+	if !ok {
+		c = NewSatCounter(t.numBits, t.insertRRPV)
+		t.rrpv[b] = c
+	}
+	return c
+}
+
+// ageAll increments the RRPV of every valid, unlocked block in the set,
+// walking the intrusive list directly instead of a slice snapshot.
+func (t *rrpvTracker) ageAll(set *Set) {
+	set.ForEachFromLRU(func(b *Block) bool {
+		if !b.IsLocked && b.IsValid {
+			t.counter(b).Inc()
+		}
+		return true
+	})
+}
+
+// reset clears all bookkeeping, forgetting every block's RRPV and hit
+// history.
+func (t *rrpvTracker) reset() {
+	t.rrpv = make(map[*Block]*SatCounter)
+	t.hit = make(map[*Block]bool)
+}
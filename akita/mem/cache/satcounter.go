@@ -0,0 +1,49 @@
+package cache
+
+// SatCounter is a small saturating counter of a fixed bit width. It never
+// under- or overflows: Inc() and Dec() clamp at Max() and 0 respectively.
+// RRIP-family victim finders use it to hold per-block RRPVs and PSEL-style
+// selector counters without each having to reimplement clamping.
+type SatCounter struct {
+	bits int
+	val  uint
+}
+
+// NewSatCounter returns a counter of the given bit width, clamping
+// initial into range if it's out of bounds.
+func NewSatCounter(bits int, initial uint) *SatCounter {
+	c := &SatCounter{bits: bits}
+	c.Set(initial)
+	return c
+}
+
+// Inc increments the counter, saturating at Max().
+func (c *SatCounter) Inc() {
+	if c.val < c.Max() {
+		c.val++
+	}
+}
+
+// Dec decrements the counter, saturating at 0.
+func (c *SatCounter) Dec() {
+	if c.val > 0 {
+		c.val--
+	}
+}
+
+// Set assigns the counter's value, clamping v into [0, Max()].
+func (c *SatCounter) Set(v uint) {
+	if v > c.Max() {
+		v = c.Max()
+	}
+	c.val = v
+}
+
+// Val returns the counter's current value.
+func (c *SatCounter) Val() uint { return c.val }
+
+// Max returns the largest value the counter can hold, (1<<bits)-1.
+func (c *SatCounter) Max() uint { return uint(1)<<uint(c.bits) - 1 }
+
+// IsSaturated reports whether the counter is at its maximum value.
+func (c *SatCounter) IsSaturated() bool { return c.val == c.Max() }
@@ -0,0 +1,47 @@
+package cache
+
+import "testing"
+
+func TestSatCounterSaturates(t *testing.T) {
+	c := NewSatCounter(2, 0)
+	for i := 0; i < 10; i++ {
+		c.Inc()
+	}
+	if got, want := c.Val(), c.Max(); got != want {
+		t.Fatalf("Inc() past max: Val() = %d, want Max() (%d)", got, want)
+	}
+	if !c.IsSaturated() {
+		t.Fatal("IsSaturated() = false at Max()")
+	}
+
+	for i := 0; i < 10; i++ {
+		c.Dec()
+	}
+	if got := c.Val(); got != 0 {
+		t.Fatalf("Dec() past zero: Val() = %d, want 0", got)
+	}
+}
+
+func TestSatCounterSetClamps(t *testing.T) {
+	c := NewSatCounter(1, 0)
+	c.Set(100)
+	if got, want := c.Val(), c.Max(); got != want {
+		t.Fatalf("Set(100) on 1-bit counter: Val() = %d, want Max() (%d)", got, want)
+	}
+}
+
+func TestSatCounterWidth(t *testing.T) {
+	cases := []struct {
+		bits int
+		max  uint
+	}{
+		{1, 1},
+		{2, 3},
+		{10, 1023},
+	}
+	for _, c := range cases {
+		if got := NewSatCounter(c.bits, 0).Max(); got != c.max {
+			t.Fatalf("NewSatCounter(%d, 0).Max() = %d, want %d", c.bits, got, c.max)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cache
+
+// Set groups the Blocks that share a cache index (an associativity-way
+// group).
+//
+// Block order is kept as an intrusive doubly linked list, the same
+// pattern goleveldb's lru cache uses: head is a sentinel node that is
+// never itself a real block, head.next is the LRU-most (oldest) block
+// and head.prev is the MRU-most (newest) block. Touching or inserting a
+// block is an O(1) pointer relink; there is no slice to scan or shift.
+type Set struct {
+	head Block
+}
+
+// ensureInit lazily wires the sentinel into a one-node ring the first
+// time the Set is used, so the zero value of Set is ready to use.
+func (s *Set) ensureInit() {
+	if s.head.next == nil {
+		s.head.next = &s.head
+		s.head.prev = &s.head
+	}
+}
+
+// InsertMRU links a block, which must not already be in the list, at the
+// MRU end.
+func (s *Set) InsertMRU(b *Block) {
+	s.ensureInit()
+	b.prev = s.head.prev
+	b.next = &s.head
+	s.head.prev.next = b
+	s.head.prev = b
+}
+
+// Remove unlinks b from the set's list. It is a no-op if b is not
+// currently linked.
+func (s *Set) Remove(b *Block) {
+	if b.prev == nil || b.next == nil {
+		return
+	}
+	b.prev.next = b.next
+	b.next.prev = b.prev
+	b.prev, b.next = nil, nil
+}
+
+// TouchMRU moves a block, which must already be in the list, to the MRU
+// end. Call this on a hit.
+func (s *Set) TouchMRU(b *Block) {
+	s.Remove(b)
+	s.InsertMRU(b)
+}
+
+// ForEachFromLRU walks the set's blocks from LRU to MRU, calling f on
+// each. It stops early if f returns false.
+func (s *Set) ForEachFromLRU(f func(b *Block) bool) {
+	s.ensureInit()
+	for b := s.head.next; b != &s.head; b = b.next {
+		if !f(b) {
+			return
+		}
+	}
+}
+
+// LRUQueue returns a snapshot slice of the set's blocks ordered from LRU
+// to MRU. It allocates on every call; it exists so callers written
+// against the old slice-based API keep working, and is not itself the
+// canonical block order - the intrusive list is.
+func (s *Set) LRUQueue() []*Block {
+	s.ensureInit()
+	queue := make([]*Block, 0)
+	s.ForEachFromLRU(func(b *Block) bool {
+		queue = append(queue, b)
+		return true
+	})
+	return queue
+}
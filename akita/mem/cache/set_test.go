@@ -0,0 +1,101 @@
+package cache
+
+import "testing"
+
+func lruOrder(s *Set) []*Block {
+	var order []*Block
+	s.ForEachFromLRU(func(b *Block) bool {
+		order = append(order, b)
+		return true
+	})
+	return order
+}
+
+func TestSetInsertMRUOrder(t *testing.T) {
+	s := &Set{}
+	a, b, c := &Block{}, &Block{}, &Block{}
+	s.InsertMRU(a)
+	s.InsertMRU(b)
+	s.InsertMRU(c)
+
+	got := lruOrder(s)
+	want := []*Block{a, b, c}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order = %v, want [a b c]", got)
+		}
+	}
+}
+
+func TestSetTouchMRUMovesToBack(t *testing.T) {
+	s := &Set{}
+	a, b, c := &Block{}, &Block{}, &Block{}
+	s.InsertMRU(a)
+	s.InsertMRU(b)
+	s.InsertMRU(c)
+
+	s.TouchMRU(a) // a was LRU-most; touching it must move it to MRU-most
+
+	got := lruOrder(s)
+	want := []*Block{b, c, a}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("order after TouchMRU(a) = %v, want [b c a]", got)
+		}
+	}
+}
+
+func TestSetRemove(t *testing.T) {
+	s := &Set{}
+	a, b, c := &Block{}, &Block{}, &Block{}
+	s.InsertMRU(a)
+	s.InsertMRU(b)
+	s.InsertMRU(c)
+
+	s.Remove(b)
+
+	got := lruOrder(s)
+	if len(got) != 2 || got[0] != a || got[1] != c {
+		t.Fatalf("order after Remove(b) = %v, want [a c]", got)
+	}
+
+	// Removing again must be a no-op, not a panic or a corrupted list.
+	s.Remove(b)
+}
+
+func TestLRUVictimFinderEvictsLRUMost(t *testing.T) {
+	finder := NewLRUVictimFinder()
+	set := newTestSet(2)
+
+	a := finder.FindVictim(set)
+	a.IsValid = true
+	set.TouchMRU(a)
+	b := finder.FindVictim(set)
+	b.IsValid = true
+	set.TouchMRU(b)
+
+	// Both blocks are now valid; a is LRU-most since b was touched last.
+	victim := finder.FindVictim(set)
+	if victim != a {
+		t.Fatalf("FindVictim on a full set = %v, want the LRU-most block (a)", victim)
+	}
+}
+
+func TestLRUVictimFinderHitProtectsBlock(t *testing.T) {
+	finder := NewLRUVictimFinder()
+	set := newTestSet(2)
+
+	a := finder.FindVictim(set)
+	a.IsValid = true
+	set.TouchMRU(a)
+	b := finder.FindVictim(set)
+	b.IsValid = true
+	set.TouchMRU(b)
+
+	set.TouchMRU(a) // simulate a hit on a: it's no longer LRU-most
+
+	victim := finder.FindVictim(set)
+	if victim != b {
+		t.Fatalf("FindVictim after touching a = %v, want b", victim)
+	}
+}